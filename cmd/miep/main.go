@@ -0,0 +1,53 @@
+// Command miep is the MIEP CLI: a thin wrapper around pkg/miep that loads a
+// source file from the command line and runs it against the real stdio.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fygar256/game_language/pkg/miep"
+	"github.com/fygar256/game_language/pkg/syntax"
+)
+
+func main() {
+	dump := flag.Bool("dump", false, "dump the parsed syntax tree instead of running the program")
+	sandbox := flag.Bool("sandbox", false, "disable *SH and file *LD/*SV/*RS/*AS")
+	force := flag.Bool("force", false, "let *RS accept a snapshot taken against a different program")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Println("Usage: miep [-dump] [-sandbox] [-force] file")
+		return
+	}
+
+	interp := miep.New(miep.Options{Sandbox: *sandbox, Force: *force})
+	var err error
+	if *sandbox {
+		// LoadFile refuses filesystem access in sandbox mode, so the program
+		// named on the command line is read here instead.
+		var data []byte
+		if data, err = os.ReadFile(flag.Arg(0)); err == nil {
+			err = interp.LoadString(string(data))
+		}
+	} else {
+		err = interp.LoadFile(flag.Arg(0))
+	}
+	if err != nil {
+		fmt.Printf("Error loading file: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *dump {
+		syntax.Fdump(os.Stdout, interp.Program())
+		return
+	}
+
+	if err := interp.Run(context.Background()); err != nil && !errors.Is(err, miep.ErrQuit) {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,353 @@
+// Package asm6502 is a two-pass assembler and disassembler for the
+// documented 6502 instruction set (pkg/cpu6502), targeting MIEP's flat
+// 64KB memory array: *AS assembles a source file directly into memory at a
+// given origin, and *DA disassembles a range of it back to text.
+package asm6502
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fygar256/game_language/pkg/cpu6502"
+)
+
+// AssembleError reports a problem with one line of assembly source.
+type AssembleError struct {
+	Line   int
+	Reason string
+}
+
+func (e *AssembleError) Error() string {
+	return fmt.Sprintf("asm6502: line %d: %s", e.Line, e.Reason)
+}
+
+// instruction is a parsed source line with everything needed to size it
+// (pass one) and then emit it once all labels are known (pass two).
+type instruction struct {
+	lineNo    int
+	label     string // label defined on this line, if any
+	equ       bool   // true if this line is a "LABEL .EQU expr" definition
+	equExpr   string
+	directive string // ".ORG", ".BYTE", ".WORD", or "" for a real instruction
+	args      string // directive argument text, or the instruction operand text
+	mnemonic  string
+	address   uint16 // set during pass one
+	size      int    // bytes this line emits, set during pass one
+}
+
+// Assemble performs a two-pass assembly of source, resolving labels on pass
+// one and emitting bytes directly into mem starting at origin on pass two.
+// It returns the label table, useful for callers that want to print where
+// things landed.
+func Assemble(source []byte, origin uint16, mem cpu6502.Memory) (map[string]uint16, error) {
+	lines, err := parseLines(source)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]uint16{}
+
+	// Pass one: walk the source computing each line's address and size.
+	// Addressing mode (and therefore size) depends only on operand syntax
+	// (a "$xx" literal is zero page, "$xxxx" is absolute, a bare label is
+	// always absolute), never on a label's resolved address, so this is
+	// accurate even for forward references.
+	addr := origin
+	for _, ln := range lines {
+		if ln.equ {
+			continue // resolved after pass one, once labels exist
+		}
+		if ln.label != "" {
+			labels[ln.label] = addr
+		}
+
+		switch ln.directive {
+		case ".ORG":
+			v, err := strconv.ParseUint(strings.TrimPrefix(ln.args, "$"), 16, 32)
+			if err != nil {
+				return nil, &AssembleError{Line: ln.lineNo, Reason: "bad .ORG address"}
+			}
+			addr = uint16(v)
+			continue
+		case ".BYTE":
+			ln.address = addr
+			ln.size = len(splitArgs(ln.args))
+			addr += uint16(ln.size)
+			continue
+		case ".WORD":
+			ln.address = addr
+			ln.size = len(splitArgs(ln.args)) * 2
+			addr += uint16(ln.size)
+			continue
+		}
+
+		if ln.mnemonic == "" {
+			continue // label-only line
+		}
+
+		mode, err := operandMode(ln.mnemonic, ln.args)
+		if err != nil {
+			return nil, &AssembleError{Line: ln.lineNo, Reason: err.Error()}
+		}
+		opcode, ok := cpu6502.Mnemonics[ln.mnemonic][mode]
+		if !ok {
+			return nil, &AssembleError{Line: ln.lineNo, Reason: fmt.Sprintf("%s does not support this addressing mode", ln.mnemonic)}
+		}
+		ln.address = addr
+		ln.size = cpu6502.Opcodes[opcode].Bytes
+		addr += uint16(ln.size)
+	}
+
+	for _, ln := range lines {
+		if ln.equ {
+			v, err := evalNumber(ln.equExpr, labels)
+			if err != nil {
+				return nil, &AssembleError{Line: ln.lineNo, Reason: err.Error()}
+			}
+			labels[ln.label] = v
+		}
+	}
+
+	// Pass two: every label now has an address, so emit real bytes.
+	for _, ln := range lines {
+		if ln.equ || ln.directive == ".ORG" {
+			continue
+		}
+		switch ln.directive {
+		case ".BYTE":
+			for i, tok := range splitArgs(ln.args) {
+				v, err := evalNumber(tok, labels)
+				if err != nil {
+					return nil, &AssembleError{Line: ln.lineNo, Reason: err.Error()}
+				}
+				mem.Write(ln.address+uint16(i), byte(v))
+			}
+			continue
+		case ".WORD":
+			for i, tok := range splitArgs(ln.args) {
+				v, err := evalNumber(tok, labels)
+				if err != nil {
+					return nil, &AssembleError{Line: ln.lineNo, Reason: err.Error()}
+				}
+				mem.Write(ln.address+uint16(i*2), byte(v))
+				mem.Write(ln.address+uint16(i*2)+1, byte(v>>8))
+			}
+			continue
+		}
+
+		if ln.mnemonic == "" {
+			continue
+		}
+
+		mode, err := operandMode(ln.mnemonic, ln.args)
+		if err != nil {
+			return nil, &AssembleError{Line: ln.lineNo, Reason: err.Error()}
+		}
+		opcode := cpu6502.Mnemonics[ln.mnemonic][mode]
+		mem.Write(ln.address, opcode)
+
+		if mode == cpu6502.Implied || mode == cpu6502.Accumulator {
+			continue
+		}
+
+		value, err := operandValue(mode, ln.args, labels)
+		if err != nil {
+			return nil, &AssembleError{Line: ln.lineNo, Reason: err.Error()}
+		}
+
+		if mode == cpu6502.Relative {
+			offset := int32(value) - int32(ln.address) - 2
+			if offset < -128 || offset > 127 {
+				return nil, &AssembleError{Line: ln.lineNo, Reason: "branch target out of range"}
+			}
+			mem.Write(ln.address+1, byte(int8(offset)))
+			continue
+		}
+
+		mem.Write(ln.address+1, byte(value))
+		if ln.size == 3 {
+			mem.Write(ln.address+2, byte(value>>8))
+		}
+	}
+
+	return labels, nil
+}
+
+func mnemonicIsBranch(mnemonic string) bool {
+	switch mnemonic {
+	case "BEQ", "BNE", "BCC", "BCS", "BPL", "BMI", "BVC", "BVS":
+		return true
+	}
+	return false
+}
+
+// operandMode determines the addressing mode an operand's syntax selects.
+// It never needs label addresses: a "$xx" literal is always zero page, a
+// "$xxxx" literal always absolute, and a bare label is always treated as
+// absolute (labels live above the zero page in all but contrived programs).
+func operandMode(mnemonic, args string) (cpu6502.AddrMode, error) {
+	if mnemonicIsBranch(mnemonic) {
+		return cpu6502.Relative, nil
+	}
+	if args == "" {
+		if _, ok := cpu6502.Mnemonics[mnemonic][cpu6502.Accumulator]; ok {
+			return cpu6502.Accumulator, nil
+		}
+		return cpu6502.Implied, nil
+	}
+	if strings.HasPrefix(args, "#") {
+		return cpu6502.Immediate, nil
+	}
+	if strings.HasPrefix(args, "(") {
+		inner := strings.TrimPrefix(args, "(")
+		switch {
+		case strings.HasSuffix(args, ",X)"):
+			return cpu6502.IndirectX, nil
+		case strings.HasSuffix(args, "),Y"):
+			return cpu6502.IndirectY, nil
+		case strings.HasSuffix(inner, ")"):
+			return cpu6502.Indirect, nil
+		}
+		return 0, fmt.Errorf("malformed indirect operand %q", args)
+	}
+
+	base, indexedByX, indexedByY := args, false, false
+	switch {
+	case strings.HasSuffix(args, ",X"):
+		base, indexedByX = strings.TrimSuffix(args, ",X"), true
+	case strings.HasSuffix(args, ",Y"):
+		base, indexedByY = strings.TrimSuffix(args, ",Y"), true
+	}
+
+	zeroPage := isZeroPage(base)
+	switch {
+	case indexedByX && zeroPage:
+		return cpu6502.ZeroPageX, nil
+	case indexedByX:
+		return cpu6502.AbsoluteX, nil
+	case indexedByY && zeroPage:
+		return cpu6502.ZeroPageY, nil
+	case indexedByY:
+		return cpu6502.AbsoluteY, nil
+	case zeroPage:
+		return cpu6502.ZeroPage, nil
+	}
+	return cpu6502.Absolute, nil
+}
+
+// isZeroPage reports whether an un-indexed operand token addresses the
+// zero page: a "$xx" literal with at most two hex digits, or a decimal
+// literal <= 255. A bare label is never zero page.
+func isZeroPage(tok string) bool {
+	if strings.HasPrefix(tok, "$") {
+		return len(tok)-1 <= 2
+	}
+	if v, err := strconv.ParseUint(tok, 10, 32); err == nil {
+		return v <= 0xFF
+	}
+	return false
+}
+
+// operandValue extracts the numeric operand (target address, immediate
+// value, or branch target) from an operand string once its addressing mode
+// is known and every label is resolved.
+func operandValue(mode cpu6502.AddrMode, args string, labels map[string]uint16) (uint16, error) {
+	tok := args
+	switch mode {
+	case cpu6502.Immediate:
+		tok = strings.TrimPrefix(args, "#")
+	case cpu6502.IndirectX:
+		tok = strings.TrimSuffix(strings.TrimPrefix(args, "("), ",X)")
+	case cpu6502.IndirectY:
+		tok = strings.TrimSuffix(strings.TrimPrefix(args, "("), "),Y")
+	case cpu6502.Indirect:
+		tok = strings.TrimSuffix(strings.TrimPrefix(args, "("), ")")
+	case cpu6502.ZeroPageX, cpu6502.AbsoluteX:
+		tok = strings.TrimSuffix(args, ",X")
+	case cpu6502.ZeroPageY, cpu6502.AbsoluteY:
+		tok = strings.TrimSuffix(args, ",Y")
+	}
+	return evalNumber(strings.TrimSpace(tok), labels)
+}
+
+func parseLines(source []byte) ([]*instruction, error) {
+	var lines []*instruction
+	scanner := bufio.NewScanner(strings.NewReader(string(source)))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, ';'); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		ln := &instruction{lineNo: lineNo}
+
+		switch {
+		case strings.HasPrefix(text, ".ORG"):
+			ln.directive = ".ORG"
+			ln.args = strings.TrimSpace(strings.TrimPrefix(text[len(".ORG"):], " "))
+		case strings.HasPrefix(text, ".BYTE"):
+			ln.directive = ".BYTE"
+			ln.args = strings.TrimSpace(text[len(".BYTE"):])
+		case strings.HasPrefix(text, ".WORD"):
+			ln.directive = ".WORD"
+			ln.args = strings.TrimSpace(text[len(".WORD"):])
+		case strings.Contains(text, ".EQU"):
+			idx := strings.Index(text, ".EQU")
+			ln.label = strings.TrimSpace(text[:idx])
+			ln.equ = true
+			ln.equExpr = strings.TrimSpace(text[idx+len(".EQU"):])
+		case strings.HasSuffix(text, ":"):
+			ln.label = strings.TrimSpace(strings.TrimSuffix(text, ":"))
+		default:
+			fields := strings.SplitN(text, " ", 2)
+			ln.mnemonic = strings.ToUpper(strings.TrimSpace(fields[0]))
+			if len(fields) > 1 {
+				ln.args = strings.ReplaceAll(strings.TrimSpace(fields[1]), " ", "")
+			}
+		}
+
+		lines = append(lines, ln)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func splitArgs(args string) []string {
+	if strings.TrimSpace(args) == "" {
+		return nil
+	}
+	parts := strings.Split(args, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func evalNumber(tok string, labels map[string]uint16) (uint16, error) {
+	tok = strings.TrimSpace(tok)
+	if strings.HasPrefix(tok, "$") {
+		v, err := strconv.ParseUint(tok[1:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("bad hex constant %q", tok)
+		}
+		return uint16(v), nil
+	}
+	if v, ok := labels[tok]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(tok, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("undefined symbol %q", tok)
+	}
+	return uint16(v), nil
+}
@@ -0,0 +1,74 @@
+package asm6502
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fygar256/game_language/pkg/cpu6502"
+)
+
+// Disassemble writes a listing of the bytes in [start, end) to w, one
+// instruction per line as "$addr: mnemonic operand". Bytes that don't
+// decode to a documented opcode are emitted as ".BYTE $xx" so a range that
+// mixes code and data still disassembles completely.
+func Disassemble(mem cpu6502.Memory, start, end uint16, w io.Writer) error {
+	addr := start
+	for addr < end {
+		opcode := mem.Read(addr)
+		info := cpu6502.Opcodes[opcode]
+		if info == nil {
+			fmt.Fprintf(w, "$%04X: .BYTE $%02X\n", addr, opcode)
+			addr++
+			continue
+		}
+
+		operand := formatOperand(mem, addr, info)
+		if operand == "" {
+			fmt.Fprintf(w, "$%04X: %s\n", addr, info.Mnemonic)
+		} else {
+			fmt.Fprintf(w, "$%04X: %s %s\n", addr, info.Mnemonic, operand)
+		}
+		addr += uint16(info.Bytes)
+	}
+	return nil
+}
+
+func formatOperand(mem cpu6502.Memory, addr uint16, info *cpu6502.OpInfo) string {
+	switch info.Mode {
+	case cpu6502.Implied:
+		return ""
+	case cpu6502.Accumulator:
+		return "A"
+	case cpu6502.Immediate:
+		return fmt.Sprintf("#$%02X", mem.Read(addr+1))
+	case cpu6502.ZeroPage:
+		return fmt.Sprintf("$%02X", mem.Read(addr+1))
+	case cpu6502.ZeroPageX:
+		return fmt.Sprintf("$%02X,X", mem.Read(addr+1))
+	case cpu6502.ZeroPageY:
+		return fmt.Sprintf("$%02X,Y", mem.Read(addr+1))
+	case cpu6502.Absolute:
+		return fmt.Sprintf("$%04X", read16(mem, addr+1))
+	case cpu6502.AbsoluteX:
+		return fmt.Sprintf("$%04X,X", read16(mem, addr+1))
+	case cpu6502.AbsoluteY:
+		return fmt.Sprintf("$%04X,Y", read16(mem, addr+1))
+	case cpu6502.Indirect:
+		return fmt.Sprintf("($%04X)", read16(mem, addr+1))
+	case cpu6502.IndirectX:
+		return fmt.Sprintf("($%02X,X)", mem.Read(addr+1))
+	case cpu6502.IndirectY:
+		return fmt.Sprintf("($%02X),Y", mem.Read(addr+1))
+	case cpu6502.Relative:
+		offset := int8(mem.Read(addr + 1))
+		target := uint16(int32(addr) + 2 + int32(offset))
+		return fmt.Sprintf("$%04X", target)
+	}
+	return ""
+}
+
+func read16(mem cpu6502.Memory, addr uint16) uint16 {
+	lo := uint16(mem.Read(addr))
+	hi := uint16(mem.Read(addr + 1))
+	return hi<<8 | lo
+}
@@ -0,0 +1,376 @@
+// Package cpu6502 emulates the documented instruction set of the MOS 6502
+// against an arbitrary Memory, so MIEP's flat 64KB memory array can be
+// executed as native code via the *EX command.
+package cpu6502
+
+import "fmt"
+
+// Status flag bits of the P register.
+const (
+	FlagC = 1 << 0 // Carry
+	FlagZ = 1 << 1 // Zero
+	FlagI = 1 << 2 // Interrupt disable
+	FlagD = 1 << 3 // Decimal (unused by MIEP, no BCD arithmetic)
+	FlagB = 1 << 4 // Break
+	Flag1 = 1 << 5 // Unused, always set
+	FlagV = 1 << 6 // Overflow
+	FlagN = 1 << 7 // Negative
+)
+
+// Memory is the byte-addressable space a CPU executes against. MIEP's
+// Interpreter.memory satisfies this via a small adapter so *AS/*DA/*EX can
+// share the same 64KB array BASIC-side code already reads and writes.
+type Memory interface {
+	Read(addr uint16) byte
+	Write(addr uint16, v byte)
+}
+
+// CPU holds the 6502 register file: A/X/Y/SP/PC/P, the pseudo-variables
+// MIEP exposes to BASIC-side code after *EX returns.
+type CPU struct {
+	A, X, Y byte
+	SP      byte
+	PC      uint16
+	P       byte
+}
+
+// NewCPU returns a CPU with the power-on flag state (interrupts disabled,
+// the unused flag bit set) and SP at the top of the stack page.
+func NewCPU() *CPU {
+	return &CPU{SP: 0xFD, P: Flag1 | FlagI}
+}
+
+func (c *CPU) setZN(v byte) {
+	if v == 0 {
+		c.P |= FlagZ
+	} else {
+		c.P &^= FlagZ
+	}
+	if v&0x80 != 0 {
+		c.P |= FlagN
+	} else {
+		c.P &^= FlagN
+	}
+}
+
+func (c *CPU) flag(mask byte) bool { return c.P&mask != 0 }
+
+func (c *CPU) setFlag(mask byte, v bool) {
+	if v {
+		c.P |= mask
+	} else {
+		c.P &^= mask
+	}
+}
+
+func (c *CPU) push(mem Memory, v byte) {
+	mem.Write(0x0100+uint16(c.SP), v)
+	c.SP--
+}
+
+func (c *CPU) pop(mem Memory) byte {
+	c.SP++
+	return mem.Read(0x0100 + uint16(c.SP))
+}
+
+func (c *CPU) push16(mem Memory, v uint16) {
+	c.push(mem, byte(v>>8))
+	c.push(mem, byte(v))
+}
+
+func (c *CPU) pop16(mem Memory) uint16 {
+	lo := uint16(c.pop(mem))
+	hi := uint16(c.pop(mem))
+	return hi<<8 | lo
+}
+
+func read16(mem Memory, addr uint16) uint16 {
+	lo := uint16(mem.Read(addr))
+	hi := uint16(mem.Read(addr + 1))
+	return hi<<8 | lo
+}
+
+// Run steps the CPU starting at pc until a BRK instruction executes or PC
+// reaches stopAt (pass -1 to run until BRK only).
+func (c *CPU) Run(mem Memory, pc uint16, stopAt int32) error {
+	c.PC = pc
+	for {
+		if stopAt >= 0 && c.PC == uint16(stopAt) {
+			return nil
+		}
+		brk, err := c.Step(mem)
+		if err != nil {
+			return err
+		}
+		if brk {
+			return nil
+		}
+	}
+}
+
+// Step executes a single instruction at c.PC, advancing it, and reports
+// whether the instruction was BRK.
+func (c *CPU) Step(mem Memory) (brk bool, err error) {
+	opcode := mem.Read(c.PC)
+	info := Opcodes[opcode]
+	if info == nil {
+		return false, &UnknownOpcodeError{Opcode: opcode, PC: c.PC}
+	}
+
+	operandAddr, operandVal, branchTarget := c.decodeOperand(mem, info.Mode)
+	c.PC += uint16(info.Bytes)
+
+	switch info.Mnemonic {
+	case "LDA":
+		c.A = operandVal
+		c.setZN(c.A)
+	case "LDX":
+		c.X = operandVal
+		c.setZN(c.X)
+	case "LDY":
+		c.Y = operandVal
+		c.setZN(c.Y)
+	case "STA":
+		mem.Write(operandAddr, c.A)
+	case "STX":
+		mem.Write(operandAddr, c.X)
+	case "STY":
+		mem.Write(operandAddr, c.Y)
+	case "TAX":
+		c.X = c.A
+		c.setZN(c.X)
+	case "TAY":
+		c.Y = c.A
+		c.setZN(c.Y)
+	case "TXA":
+		c.A = c.X
+		c.setZN(c.A)
+	case "TYA":
+		c.A = c.Y
+		c.setZN(c.A)
+	case "TSX":
+		c.X = c.SP
+		c.setZN(c.X)
+	case "TXS":
+		c.SP = c.X
+	case "PHA":
+		c.push(mem, c.A)
+	case "PLA":
+		c.A = c.pop(mem)
+		c.setZN(c.A)
+	case "PHP":
+		c.push(mem, c.P|FlagB|Flag1)
+	case "PLP":
+		c.P = c.pop(mem)&^FlagB | Flag1
+	case "ADC":
+		c.adc(operandVal)
+	case "SBC":
+		c.adc(operandVal ^ 0xFF)
+	case "AND":
+		c.A &= operandVal
+		c.setZN(c.A)
+	case "ORA":
+		c.A |= operandVal
+		c.setZN(c.A)
+	case "EOR":
+		c.A ^= operandVal
+		c.setZN(c.A)
+	case "BIT":
+		c.setFlag(FlagZ, c.A&operandVal == 0)
+		c.setFlag(FlagV, operandVal&0x40 != 0)
+		c.setFlag(FlagN, operandVal&0x80 != 0)
+	case "CMP":
+		c.compare(c.A, operandVal)
+	case "CPX":
+		c.compare(c.X, operandVal)
+	case "CPY":
+		c.compare(c.Y, operandVal)
+	case "INC":
+		v := mem.Read(operandAddr) + 1
+		mem.Write(operandAddr, v)
+		c.setZN(v)
+	case "DEC":
+		v := mem.Read(operandAddr) - 1
+		mem.Write(operandAddr, v)
+		c.setZN(v)
+	case "INX":
+		c.X++
+		c.setZN(c.X)
+	case "INY":
+		c.Y++
+		c.setZN(c.Y)
+	case "DEX":
+		c.X--
+		c.setZN(c.X)
+	case "DEY":
+		c.Y--
+		c.setZN(c.Y)
+	case "ASL":
+		c.shift(mem, info.Mode, operandAddr, true, false)
+	case "LSR":
+		c.shift(mem, info.Mode, operandAddr, false, false)
+	case "ROL":
+		c.shift(mem, info.Mode, operandAddr, true, true)
+	case "ROR":
+		c.shift(mem, info.Mode, operandAddr, false, true)
+	case "JMP":
+		c.PC = operandAddr
+	case "JSR":
+		c.push16(mem, c.PC-1)
+		c.PC = operandAddr
+	case "RTS":
+		c.PC = c.pop16(mem) + 1
+	case "RTI":
+		c.P = c.pop(mem)&^FlagB | Flag1
+		c.PC = c.pop16(mem)
+	case "BEQ":
+		c.branch(c.flag(FlagZ), branchTarget)
+	case "BNE":
+		c.branch(!c.flag(FlagZ), branchTarget)
+	case "BCC":
+		c.branch(!c.flag(FlagC), branchTarget)
+	case "BCS":
+		c.branch(c.flag(FlagC), branchTarget)
+	case "BPL":
+		c.branch(!c.flag(FlagN), branchTarget)
+	case "BMI":
+		c.branch(c.flag(FlagN), branchTarget)
+	case "BVC":
+		c.branch(!c.flag(FlagV), branchTarget)
+	case "BVS":
+		c.branch(c.flag(FlagV), branchTarget)
+	case "CLC":
+		c.setFlag(FlagC, false)
+	case "SEC":
+		c.setFlag(FlagC, true)
+	case "CLI":
+		c.setFlag(FlagI, false)
+	case "SEI":
+		c.setFlag(FlagI, true)
+	case "CLD":
+		c.setFlag(FlagD, false)
+	case "SED":
+		c.setFlag(FlagD, true)
+	case "CLV":
+		c.setFlag(FlagV, false)
+	case "NOP":
+		// nothing
+	case "BRK":
+		c.push16(mem, c.PC)
+		c.push(mem, c.P|FlagB|Flag1)
+		c.setFlag(FlagI, true)
+		c.PC = read16(mem, 0xFFFE)
+		return true, nil
+	default:
+		return false, &UnknownOpcodeError{Opcode: opcode, PC: c.PC}
+	}
+
+	return false, nil
+}
+
+func (c *CPU) adc(v byte) {
+	sum := uint16(c.A) + uint16(v)
+	if c.flag(FlagC) {
+		sum++
+	}
+	result := byte(sum)
+	c.setFlag(FlagC, sum > 0xFF)
+	c.setFlag(FlagV, (c.A^v)&0x80 == 0 && (c.A^result)&0x80 != 0)
+	c.A = result
+	c.setZN(c.A)
+}
+
+func (c *CPU) compare(reg, v byte) {
+	c.setFlag(FlagC, reg >= v)
+	c.setZN(reg - v)
+}
+
+func (c *CPU) shift(mem Memory, mode AddrMode, addr uint16, left, rotate bool) {
+	var v byte
+	accumulator := mode == Accumulator
+	if accumulator {
+		v = c.A
+	} else {
+		v = mem.Read(addr)
+	}
+
+	var carryOut bool
+	var result byte
+	if left {
+		carryOut = v&0x80 != 0
+		result = v << 1
+		if rotate && c.flag(FlagC) {
+			result |= 1
+		}
+	} else {
+		carryOut = v&0x01 != 0
+		result = v >> 1
+		if rotate && c.flag(FlagC) {
+			result |= 0x80
+		}
+	}
+
+	c.setFlag(FlagC, carryOut)
+	c.setZN(result)
+	if accumulator {
+		c.A = result
+	} else {
+		mem.Write(addr, result)
+	}
+}
+
+func (c *CPU) branch(taken bool, target uint16) {
+	if taken {
+		c.PC = target
+	}
+}
+
+// decodeOperand resolves an instruction's operand for every addressing mode
+// MIEP's *AS assembler supports, returning the effective address (for
+// stores/RMW/JMP/JSR), the loaded value (for everything that reads an
+// operand) and, for relative branches only, the branch target.
+func (c *CPU) decodeOperand(mem Memory, mode AddrMode) (addr uint16, val byte, branchTarget uint16) {
+	switch mode {
+	case Implied, Accumulator:
+		return 0, c.A, 0
+	case Immediate:
+		addr = c.PC + 1
+		return addr, mem.Read(addr), 0
+	case ZeroPage:
+		addr = uint16(mem.Read(c.PC + 1))
+	case ZeroPageX:
+		addr = uint16(mem.Read(c.PC+1) + c.X)
+	case ZeroPageY:
+		addr = uint16(mem.Read(c.PC+1) + c.Y)
+	case Absolute:
+		addr = read16(mem, c.PC+1)
+	case AbsoluteX:
+		addr = read16(mem, c.PC+1) + uint16(c.X)
+	case AbsoluteY:
+		addr = read16(mem, c.PC+1) + uint16(c.Y)
+	case Indirect:
+		addr = read16(mem, read16(mem, c.PC+1))
+	case IndirectX:
+		ptr := uint16(mem.Read(c.PC+1) + c.X)
+		addr = read16(mem, ptr)
+	case IndirectY:
+		ptr := uint16(mem.Read(c.PC + 1))
+		addr = read16(mem, ptr) + uint16(c.Y)
+	case Relative:
+		offset := int8(mem.Read(c.PC + 1))
+		target := uint16(int32(c.PC) + 2 + int32(offset))
+		return 0, 0, target
+	}
+	return addr, mem.Read(addr), 0
+}
+
+// UnknownOpcodeError is returned by Step when the byte at PC isn't one of
+// the documented 6502 opcodes this package implements.
+type UnknownOpcodeError struct {
+	Opcode byte
+	PC     uint16
+}
+
+func (e *UnknownOpcodeError) Error() string {
+	return fmt.Sprintf("cpu6502: unknown opcode $%02X at $%04X", e.Opcode, e.PC)
+}
@@ -0,0 +1,239 @@
+package cpu6502
+
+// AddrMode is one of the 6502's addressing modes.
+type AddrMode int
+
+const (
+	Implied AddrMode = iota
+	Accumulator
+	Immediate
+	ZeroPage
+	ZeroPageX
+	ZeroPageY
+	Absolute
+	AbsoluteX
+	AbsoluteY
+	Indirect
+	IndirectX
+	IndirectY
+	Relative
+)
+
+// OpInfo describes one opcode byte: the mnemonic and addressing mode it
+// decodes as, and the instruction's total length in bytes (opcode +
+// operand), used to advance PC and to size instructions during assembly.
+type OpInfo struct {
+	Mnemonic string
+	Mode     AddrMode
+	Bytes    int
+}
+
+func modeBytes(mode AddrMode) int {
+	switch mode {
+	case Implied, Accumulator:
+		return 1
+	case Immediate, ZeroPage, ZeroPageX, ZeroPageY, IndirectX, IndirectY, Relative:
+		return 2
+	case Absolute, AbsoluteX, AbsoluteY, Indirect:
+		return 3
+	}
+	return 1
+}
+
+// Opcodes maps an opcode byte to the instruction it decodes as. Only the
+// documented instruction set is implemented; undocumented opcodes decode
+// to nil and fault with UnknownOpcodeError.
+var Opcodes [256]*OpInfo
+
+// Mnemonics maps a mnemonic to the opcode byte it assembles to for each
+// addressing mode it supports, the inverse of Opcodes, used by asm6502.
+var Mnemonics = map[string]map[AddrMode]byte{}
+
+func def(opcode byte, mnemonic string, mode AddrMode) {
+	info := &OpInfo{Mnemonic: mnemonic, Mode: mode, Bytes: modeBytes(mode)}
+	Opcodes[opcode] = info
+	if Mnemonics[mnemonic] == nil {
+		Mnemonics[mnemonic] = map[AddrMode]byte{}
+	}
+	Mnemonics[mnemonic][mode] = opcode
+}
+
+func init() {
+	def(0xA9, "LDA", Immediate)
+	def(0xA5, "LDA", ZeroPage)
+	def(0xB5, "LDA", ZeroPageX)
+	def(0xAD, "LDA", Absolute)
+	def(0xBD, "LDA", AbsoluteX)
+	def(0xB9, "LDA", AbsoluteY)
+	def(0xA1, "LDA", IndirectX)
+	def(0xB1, "LDA", IndirectY)
+
+	def(0xA2, "LDX", Immediate)
+	def(0xA6, "LDX", ZeroPage)
+	def(0xB6, "LDX", ZeroPageY)
+	def(0xAE, "LDX", Absolute)
+	def(0xBE, "LDX", AbsoluteY)
+
+	def(0xA0, "LDY", Immediate)
+	def(0xA4, "LDY", ZeroPage)
+	def(0xB4, "LDY", ZeroPageX)
+	def(0xAC, "LDY", Absolute)
+	def(0xBC, "LDY", AbsoluteX)
+
+	def(0x85, "STA", ZeroPage)
+	def(0x95, "STA", ZeroPageX)
+	def(0x8D, "STA", Absolute)
+	def(0x9D, "STA", AbsoluteX)
+	def(0x99, "STA", AbsoluteY)
+	def(0x81, "STA", IndirectX)
+	def(0x91, "STA", IndirectY)
+
+	def(0x86, "STX", ZeroPage)
+	def(0x96, "STX", ZeroPageY)
+	def(0x8E, "STX", Absolute)
+
+	def(0x84, "STY", ZeroPage)
+	def(0x94, "STY", ZeroPageX)
+	def(0x8C, "STY", Absolute)
+
+	def(0xAA, "TAX", Implied)
+	def(0xA8, "TAY", Implied)
+	def(0x8A, "TXA", Implied)
+	def(0x98, "TYA", Implied)
+	def(0xBA, "TSX", Implied)
+	def(0x9A, "TXS", Implied)
+	def(0x48, "PHA", Implied)
+	def(0x68, "PLA", Implied)
+	def(0x08, "PHP", Implied)
+	def(0x28, "PLP", Implied)
+
+	def(0x69, "ADC", Immediate)
+	def(0x65, "ADC", ZeroPage)
+	def(0x75, "ADC", ZeroPageX)
+	def(0x6D, "ADC", Absolute)
+	def(0x7D, "ADC", AbsoluteX)
+	def(0x79, "ADC", AbsoluteY)
+	def(0x61, "ADC", IndirectX)
+	def(0x71, "ADC", IndirectY)
+
+	def(0xE9, "SBC", Immediate)
+	def(0xE5, "SBC", ZeroPage)
+	def(0xF5, "SBC", ZeroPageX)
+	def(0xED, "SBC", Absolute)
+	def(0xFD, "SBC", AbsoluteX)
+	def(0xF9, "SBC", AbsoluteY)
+	def(0xE1, "SBC", IndirectX)
+	def(0xF1, "SBC", IndirectY)
+
+	def(0x29, "AND", Immediate)
+	def(0x25, "AND", ZeroPage)
+	def(0x35, "AND", ZeroPageX)
+	def(0x2D, "AND", Absolute)
+	def(0x3D, "AND", AbsoluteX)
+	def(0x39, "AND", AbsoluteY)
+	def(0x21, "AND", IndirectX)
+	def(0x31, "AND", IndirectY)
+
+	def(0x09, "ORA", Immediate)
+	def(0x05, "ORA", ZeroPage)
+	def(0x15, "ORA", ZeroPageX)
+	def(0x0D, "ORA", Absolute)
+	def(0x1D, "ORA", AbsoluteX)
+	def(0x19, "ORA", AbsoluteY)
+	def(0x01, "ORA", IndirectX)
+	def(0x11, "ORA", IndirectY)
+
+	def(0x49, "EOR", Immediate)
+	def(0x45, "EOR", ZeroPage)
+	def(0x55, "EOR", ZeroPageX)
+	def(0x4D, "EOR", Absolute)
+	def(0x5D, "EOR", AbsoluteX)
+	def(0x59, "EOR", AbsoluteY)
+	def(0x41, "EOR", IndirectX)
+	def(0x51, "EOR", IndirectY)
+
+	def(0x24, "BIT", ZeroPage)
+	def(0x2C, "BIT", Absolute)
+
+	def(0xC9, "CMP", Immediate)
+	def(0xC5, "CMP", ZeroPage)
+	def(0xD5, "CMP", ZeroPageX)
+	def(0xCD, "CMP", Absolute)
+	def(0xDD, "CMP", AbsoluteX)
+	def(0xD9, "CMP", AbsoluteY)
+	def(0xC1, "CMP", IndirectX)
+	def(0xD1, "CMP", IndirectY)
+
+	def(0xE0, "CPX", Immediate)
+	def(0xE4, "CPX", ZeroPage)
+	def(0xEC, "CPX", Absolute)
+
+	def(0xC0, "CPY", Immediate)
+	def(0xC4, "CPY", ZeroPage)
+	def(0xCC, "CPY", Absolute)
+
+	def(0xE6, "INC", ZeroPage)
+	def(0xF6, "INC", ZeroPageX)
+	def(0xEE, "INC", Absolute)
+	def(0xFE, "INC", AbsoluteX)
+
+	def(0xC6, "DEC", ZeroPage)
+	def(0xD6, "DEC", ZeroPageX)
+	def(0xCE, "DEC", Absolute)
+	def(0xDE, "DEC", AbsoluteX)
+
+	def(0xE8, "INX", Implied)
+	def(0xC8, "INY", Implied)
+	def(0xCA, "DEX", Implied)
+	def(0x88, "DEY", Implied)
+
+	def(0x0A, "ASL", Accumulator)
+	def(0x06, "ASL", ZeroPage)
+	def(0x16, "ASL", ZeroPageX)
+	def(0x0E, "ASL", Absolute)
+	def(0x1E, "ASL", AbsoluteX)
+
+	def(0x4A, "LSR", Accumulator)
+	def(0x46, "LSR", ZeroPage)
+	def(0x56, "LSR", ZeroPageX)
+	def(0x4E, "LSR", Absolute)
+	def(0x5E, "LSR", AbsoluteX)
+
+	def(0x2A, "ROL", Accumulator)
+	def(0x26, "ROL", ZeroPage)
+	def(0x36, "ROL", ZeroPageX)
+	def(0x2E, "ROL", Absolute)
+	def(0x3E, "ROL", AbsoluteX)
+
+	def(0x6A, "ROR", Accumulator)
+	def(0x66, "ROR", ZeroPage)
+	def(0x76, "ROR", ZeroPageX)
+	def(0x6E, "ROR", Absolute)
+	def(0x7E, "ROR", AbsoluteX)
+
+	def(0x4C, "JMP", Absolute)
+	def(0x6C, "JMP", Indirect)
+	def(0x20, "JSR", Absolute)
+	def(0x60, "RTS", Implied)
+	def(0x40, "RTI", Implied)
+
+	def(0xF0, "BEQ", Relative)
+	def(0xD0, "BNE", Relative)
+	def(0x90, "BCC", Relative)
+	def(0xB0, "BCS", Relative)
+	def(0x10, "BPL", Relative)
+	def(0x30, "BMI", Relative)
+	def(0x50, "BVC", Relative)
+	def(0x70, "BVS", Relative)
+
+	def(0x18, "CLC", Implied)
+	def(0x38, "SEC", Implied)
+	def(0x58, "CLI", Implied)
+	def(0x78, "SEI", Implied)
+	def(0xD8, "CLD", Implied)
+	def(0xF8, "SED", Implied)
+	def(0xB8, "CLV", Implied)
+
+	def(0xEA, "NOP", Implied)
+	def(0x00, "BRK", Implied)
+}
@@ -0,0 +1,39 @@
+package miep
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrQuit is returned by Run when the program executes *QU. It replaces the
+// old os.Exit(0) so embedders (test harnesses, playgrounds) get control
+// back instead of having their whole process terminated.
+var ErrQuit = errors.New("miep: program executed *QU")
+
+// ErrDivisionByZero is the cause wrapped by a RuntimeError when an
+// expression divides by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// errSandboxedLoad is the cause wrapped by a RuntimeError when *LD, *SV, *RS,
+// *AS, LoadFile, Snapshot or Restore is used on a Sandbox Interpreter.
+var errSandboxedLoad = errors.New("file access is disabled in sandbox mode")
+
+// ErrProgramMismatch is returned by Restore when a snapshot's program hash
+// doesn't match the currently loaded source and force wasn't requested, so a
+// restored FOR/GOSUB return address can't be trusted to point at valid code.
+var ErrProgramMismatch = errors.New("miep: snapshot was taken against a different program")
+
+// RuntimeError reports an error raised while running a parsed program, with
+// the MIEP line number it happened on.
+type RuntimeError struct {
+	Line int
+	Err  error
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("miep: line %d: %v", e.Line, e.Err)
+}
+
+func (e *RuntimeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,137 @@
+package miep
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/fygar256/game_language/pkg/syntax"
+)
+
+// eval walks an expression tree and returns its value. The only expression
+// that can fail is division, which reports *RuntimeError(ErrDivisionByZero)
+// instead of printing "Division by zero" and returning -1.
+func (m *Interpreter) eval(e syntax.Expr) (int16, error) {
+	switch n := e.(type) {
+	case *syntax.Const:
+		return n.Value, nil
+	case *syntax.Var:
+		return m.variables[n.Name-'A'], nil
+	case *syntax.ByteArray:
+		base := m.variables[n.Var-'A']
+		idx, err := m.eval(n.Index)
+		if err != nil {
+			return 0, err
+		}
+		return int16(m.memory[base+idx]), nil
+	case *syntax.WordArray:
+		base := m.variables[n.Var-'A']
+		idx, err := m.eval(n.Index)
+		if err != nil {
+			return 0, err
+		}
+		addr := base + idx*2
+		return int16(m.memory[addr]) | (int16(m.memory[addr+1]) << 8), nil
+	case *syntax.CPUReg:
+		if m.cpu == nil {
+			return 0, nil
+		}
+		switch n.Reg {
+		case 'A':
+			return int16(m.cpu.A), nil
+		case 'X':
+			return int16(m.cpu.X), nil
+		case 'Y':
+			return int16(m.cpu.Y), nil
+		case 'S':
+			return int16(m.cpu.SP), nil
+		case 'P':
+			return int16(m.cpu.P), nil
+		case 'C':
+			return int16(m.cpu.PC), nil
+		}
+		return 0, nil
+	case *syntax.GetCh:
+		c, _ := m.reader.ReadByte()
+		return int16(c), nil
+	case *syntax.Input:
+		line, _ := m.reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "$") {
+			val, _ := strconv.ParseInt(line[1:], 16, 32)
+			return int16(val), nil
+		}
+		val, _ := strconv.Atoi(line)
+		return int16(val), nil
+	case *syntax.UnaryOp:
+		v, err := m.eval(n.X)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case '-':
+			return -v, nil
+		case '+':
+			if v < 0 {
+				return -v, nil
+			}
+			return v, nil
+		case '#':
+			if v != 0 {
+				return 0, nil
+			}
+			return 1, nil
+		case '\'':
+			if v > 0 {
+				return int16(m.opts.Rand.Intn(int(v))), nil
+			}
+			return 0, nil
+		case '%':
+			return m.mod, nil
+		}
+		return 0, nil
+	case *syntax.BinOp:
+		v, err := m.eval(n.L)
+		if err != nil {
+			return 0, err
+		}
+		v2, err := m.eval(n.R)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case '+':
+			return v + v2, nil
+		case '-':
+			return v - v2, nil
+		case '*':
+			return v * v2, nil
+		case '/':
+			if v2 == 0 {
+				return 0, &RuntimeError{Line: m.ln, Err: ErrDivisionByZero}
+			}
+			m.mod = v % v2
+			return v / v2, nil
+		case '=':
+			return boolInt(v == v2), nil
+		case '<':
+			return boolInt(v < v2), nil
+		case 'N':
+			return boolInt(v != v2), nil
+		case 'A':
+			return boolInt(v <= v2), nil
+		case '>':
+			return boolInt(v > v2), nil
+		case 'B':
+			return boolInt(v >= v2), nil
+		}
+		return 0, nil
+	}
+	return 0, nil
+}
+
+func boolInt(b bool) int16 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,257 @@
+package miep
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fygar256/game_language/pkg/asm6502"
+	"github.com/fygar256/game_language/pkg/cpu6502"
+	"github.com/fygar256/game_language/pkg/syntax"
+)
+
+// execStmt runs one statement. It reports whether it changed pc itself
+// (jump, loop control), so run's driver loop should not auto-advance, and
+// any error that should abort Run (division by zero, *QU, a sandboxed *LD).
+func (m *Interpreter) execStmt(stmt syntax.Stmt) (bool, error) {
+	switch s := stmt.(type) {
+	case *syntax.PrintString:
+		fmt.Fprint(m.opts.Stdout, s.Value)
+	case *syntax.PrintNewline:
+		fmt.Fprintln(m.opts.Stdout)
+	case *syntax.PrintSpaces:
+		v, err := m.eval(s.Count)
+		if err != nil {
+			return false, err
+		}
+		for i := int16(0); i < v; i++ {
+			fmt.Fprint(m.opts.Stdout, " ")
+		}
+	case *syntax.PrintDec:
+		v, err := m.eval(s.Value)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprint(m.opts.Stdout, int(v))
+	case *syntax.PrintHex4:
+		v, err := m.eval(s.Value)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(m.opts.Stdout, "%04x", uint16(v))
+	case *syntax.PrintHex2:
+		v, err := m.eval(s.Value)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(m.opts.Stdout, "%02x", uint8(v))
+	case *syntax.PrintFormatted:
+		width, err := m.eval(s.Width)
+		if err != nil {
+			return false, err
+		}
+		v, err := m.eval(s.Value)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(m.opts.Stdout, fmt.Sprintf("%%%dd", width), int(v))
+	case *syntax.PrintChar:
+		v, err := m.eval(s.Value)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(m.opts.Stdout, "%c", byte(v))
+	case *syntax.Seed:
+		v, err := m.eval(s.Value)
+		if err != nil {
+			return false, err
+		}
+		m.opts.Rand.Seed(int64(v))
+	case *syntax.Goto:
+		v, err := m.eval(s.Target)
+		if err != nil {
+			return false, err
+		}
+		m.gotoLine(v)
+		return true, nil
+	case *syntax.Gosub:
+		v, err := m.eval(s.Target)
+		if err != nil {
+			return false, err
+		}
+		m.gosub(v)
+		return true, nil
+	case *syntax.Return:
+		m.returnFromSub()
+		return true, nil
+	case *syntax.Do:
+		m.doLoop()
+	case *syntax.Until:
+		return m.untilLoop(s.Cond)
+	case *syntax.Next:
+		return m.nextLoop(s.Step)
+	case *syntax.IfStmt:
+		v, err := m.eval(s.Cond)
+		if err != nil {
+			return false, err
+		}
+		if v == 0 {
+			m.pc = syntax.Pos{Line: m.pc.Line, Stmt: len(m.runProg.Lines[m.pc.Line].Stmts)}
+			return true, nil
+		}
+	case *syntax.OptCommand:
+		return false, m.execOptCommand(s)
+	case *syntax.Assemble:
+		return false, m.execAssemble(s)
+	case *syntax.Disassemble:
+		return false, m.execDisassemble(s)
+	case *syntax.Execute:
+		return false, m.execExecute(s)
+	case *syntax.Assign:
+		if err := m.execAssign(s); err != nil {
+			return false, err
+		}
+		if s.ForTo != nil {
+			toVal, err := m.eval(s.ForTo)
+			if err != nil {
+				return false, err
+			}
+			v := m.variables[s.Var-'A']
+			if v > toVal && m.forMode != 0 {
+				if next, ok := m.findLoopEnd(m.resumePos()); ok {
+					m.jumpTo(next)
+				} else {
+					m.pc = syntax.Pos{Line: -1}
+				}
+				return true, nil
+			}
+			m.stack = append(m.stack, int(s.Var-'A'), m.resumePos(), toVal)
+			m.sp += 3
+		}
+	}
+	return false, nil
+}
+
+func (m *Interpreter) execAssign(a *syntax.Assign) error {
+	varIdx := a.Var - 'A'
+	v, err := m.eval(a.Value)
+	if err != nil {
+		return err
+	}
+
+	if a.Index == nil {
+		m.variables[varIdx] = v
+		return nil
+	}
+
+	idx, err := m.eval(a.Index)
+	if err != nil {
+		return err
+	}
+	if a.Word {
+		addr := m.variables[varIdx] + idx*2
+		m.memory[addr] = byte(v)
+		m.memory[addr+1] = byte(v >> 8)
+	} else {
+		m.memory[m.variables[varIdx]+idx] = byte(v)
+	}
+	return nil
+}
+
+func (m *Interpreter) execAssemble(s *syntax.Assemble) error {
+	if m.opts.Sandbox {
+		return &RuntimeError{Line: m.ln, Err: errSandboxedLoad}
+	}
+	origin, err := m.eval(s.Origin)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(s.File)
+	if err != nil {
+		return err
+	}
+	_, err = asm6502.Assemble(data, uint16(origin), memAdapter{m})
+	return err
+}
+
+func (m *Interpreter) execDisassemble(s *syntax.Disassemble) error {
+	start, err := m.eval(s.Start)
+	if err != nil {
+		return err
+	}
+	end, err := m.eval(s.End)
+	if err != nil {
+		return err
+	}
+	return asm6502.Disassemble(memAdapter{m}, uint16(start), uint16(end), m.opts.Stdout)
+}
+
+func (m *Interpreter) execExecute(s *syntax.Execute) error {
+	addr, err := m.eval(s.Address)
+	if err != nil {
+		return err
+	}
+	stopAt := int32(-1)
+	if s.Stop != nil {
+		stop, err := m.eval(s.Stop)
+		if err != nil {
+			return err
+		}
+		stopAt = int32(uint16(stop))
+	}
+	if m.cpu == nil {
+		m.cpu = cpu6502.NewCPU()
+	}
+	return m.cpu.Run(memAdapter{m}, uint16(addr), stopAt)
+}
+
+func (m *Interpreter) execOptCommand(c *syntax.OptCommand) error {
+	switch c.Name {
+	case "LD":
+		return m.LoadFile(c.File)
+	case "QU":
+		return ErrQuit
+	case "TN":
+		m.tron = true
+	case "TF":
+		m.tron = false
+	case "SH":
+		if !m.opts.Sandbox {
+			fmt.Fprintln(m.opts.Stdout, "Shell command not supported")
+		}
+	case "FM":
+		v, err := m.eval(c.Arg)
+		if err != nil {
+			return err
+		}
+		m.forMode = int(v)
+	case "SV":
+		return m.snapshotToFile(c.File)
+	case "RS":
+		return m.restoreFromFile(c.File)
+	}
+	return nil
+}
+
+func (m *Interpreter) snapshotToFile(filename string) error {
+	if m.opts.Sandbox {
+		return &RuntimeError{Line: m.ln, Err: errSandboxedLoad}
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.Snapshot(f)
+}
+
+func (m *Interpreter) restoreFromFile(filename string) error {
+	if m.opts.Sandbox {
+		return &RuntimeError{Line: m.ln, Err: errSandboxedLoad}
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.Restore(f, m.opts.Force)
+}
@@ -0,0 +1,261 @@
+// Package miep is an embeddable implementation of the MIEP language: a
+// reentrant Interpreter with pluggable I/O, so it can run inside test
+// harnesses, web playgrounds, or as a scripting engine embedded in a larger
+// Go program, the same shape GoAWK exposes for its embedded interpreter.
+package miep
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fygar256/game_language/pkg/cpu6502"
+	"github.com/fygar256/game_language/pkg/syntax"
+)
+
+// Interpreter holds one MIEP program's variables, memory and execution
+// state. Two Interpreters share nothing, so running several concurrently
+// (each with its own Options.Rand) is safe.
+type Interpreter struct {
+	opts Options
+
+	variables [26]int16       // A-Z variables
+	memory    [65536]byte     // 64KB memory
+	source    []byte          // most recently loaded source, hashed into *SV snapshots
+	prog      *syntax.Program // most recently loaded program, target of GOTO/GOSUB
+	runProg   *syntax.Program // program the interpreter is currently walking
+	pc        syntax.Pos      // current statement
+	ln        int             // current line number, for error messages
+	stack     []interface{}   // runtime stack (Pos, int16, int frames)
+	sp        int             // stack pointer
+	tron      bool            // trace mode
+	mod       int16           // modulo result
+	forMode   int             // FOR loop mode
+	reader    *bufio.Reader   // buffers opts.Stdin for $ and ? reads
+	cpu       *cpu6502.CPU    // 6502 register file, for *EX and the &r pseudo-variables
+}
+
+// memAdapter lets pkg/asm6502 and pkg/cpu6502 address the same 64KB array
+// BASIC-side code already reads and writes via V:expr) and V(expr).
+type memAdapter struct{ m *Interpreter }
+
+func (a memAdapter) Read(addr uint16) byte     { return a.m.memory[addr] }
+func (a memAdapter) Write(addr uint16, v byte) { a.m.memory[addr] = v }
+
+// New creates an Interpreter configured by opts.
+func New(opts Options) *Interpreter {
+	opts = opts.withDefaults()
+	return &Interpreter{
+		opts:   opts,
+		reader: bufio.NewReader(opts.Stdin),
+		stack:  make([]interface{}, 0, 65536),
+	}
+}
+
+// Program returns the most recently loaded program, for tooling (e.g. the
+// miep CLI's -dump flag). It is nil until LoadString or LoadFile succeeds.
+func (m *Interpreter) Program() *syntax.Program {
+	return m.prog
+}
+
+// LoadString parses source as the program the interpreter will run and the
+// target of future GOTO/GOSUB/*LD jumps.
+func (m *Interpreter) LoadString(source string) error {
+	prog, err := syntax.Parse([]byte(source))
+	if err != nil {
+		return err
+	}
+	m.source = []byte(source)
+	m.prog = prog
+	if m.runProg == nil {
+		m.runProg = prog
+	}
+	return nil
+}
+
+// LoadFile reads and parses filename. In Sandbox mode it refuses to read
+// from the filesystem.
+func (m *Interpreter) LoadFile(filename string) error {
+	if m.opts.Sandbox {
+		return &RuntimeError{Line: m.ln, Err: errSandboxedLoad}
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	prog, err := syntax.ParseFile(filename, data)
+	if err != nil {
+		return err
+	}
+	m.source = data
+	m.prog = prog
+	if m.runProg == nil {
+		m.runProg = prog
+	}
+	return nil
+}
+
+// Run executes the currently loaded program from line 1 by walking its
+// syntax tree, checking ctx between statements so a caller can cancel or
+// time out a runaway program. It returns ErrQuit if the program executed
+// *QU, ctx.Err() if ctx was cancelled, or a *RuntimeError for things like
+// division by zero.
+func (m *Interpreter) Run(ctx context.Context) error {
+	m.gotoLine(1)
+	return m.run(ctx)
+}
+
+// run walks the AST, executing one statement per iteration.
+func (m *Interpreter) run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if m.pc.Line < 0 || m.pc.Line >= len(m.runProg.Lines) {
+			return nil
+		}
+		line := m.runProg.Lines[m.pc.Line]
+
+		if m.pc.Stmt == 0 && m.tron {
+			fmt.Fprintf(m.opts.Stdout, "[%d]", line.Num)
+		}
+		m.ln = int(line.Num)
+
+		if m.pc.Stmt >= len(line.Stmts) {
+			m.pc = syntax.Pos{Line: m.pc.Line + 1, Stmt: 0}
+			continue
+		}
+
+		jumped, err := m.execStmt(line.Stmts[m.pc.Stmt])
+		if err != nil {
+			return err
+		}
+		if !jumped {
+			m.pc.Stmt++
+		}
+	}
+}
+
+// gotoLine jumps to a line number, resolved against the most recently
+// loaded program (m.prog), via Find's map lookup / binary-search fallback.
+func (m *Interpreter) gotoLine(lineNum int16) {
+	idx, ok := m.prog.Find(lineNum)
+	if !ok {
+		m.ln = -1
+		m.pc = syntax.Pos{Line: -1}
+		return
+	}
+	m.runProg = m.prog
+	m.pc = syntax.Pos{Line: idx, Stmt: 0}
+	m.ln = int(lineNum)
+}
+
+// gosub calls a subroutine
+func (m *Interpreter) gosub(lineNum int16) {
+	m.stack = append(m.stack, m.resumePos())
+	m.sp++
+	m.gotoLine(lineNum)
+}
+
+// returnFromSub returns from subroutine
+func (m *Interpreter) returnFromSub() {
+	if m.sp >= 1 {
+		m.sp--
+		m.jumpTo(m.stack[m.sp].(syntax.Pos))
+		m.stack = m.stack[:m.sp]
+	}
+}
+
+// doLoop starts a DO loop
+func (m *Interpreter) doLoop() {
+	m.stack = append(m.stack, m.resumePos())
+	m.sp++
+}
+
+// untilLoop handles UNTIL condition. It reports whether it jumped back to
+// the matching DO, so execStmt knows whether to auto-advance pc instead.
+func (m *Interpreter) untilLoop(cond syntax.Expr) (bool, error) {
+	if m.sp < 1 {
+		return false, nil
+	}
+	m.sp--
+	savedPos := m.stack[m.sp].(syntax.Pos)
+	m.stack = m.stack[:m.sp]
+
+	v, err := m.eval(cond)
+	if err != nil {
+		return false, err
+	}
+	if v != 0 {
+		return false, nil
+	}
+	m.jumpTo(savedPos)
+	m.stack = append(m.stack, savedPos)
+	m.sp++
+	return true, nil
+}
+
+// nextLoop handles NEXT in a FOR loop. It reports whether it jumped back to
+// the loop body, so execStmt knows whether to auto-advance pc instead.
+func (m *Interpreter) nextLoop(step syntax.Expr) (bool, error) {
+	if m.sp < 3 {
+		return false, nil
+	}
+	m.sp -= 3
+	toVal := m.stack[m.sp+2].(int16)
+	savedPos := m.stack[m.sp+1].(syntax.Pos)
+	varIdx := m.stack[m.sp].(int)
+	m.stack = m.stack[:m.sp]
+
+	v, err := m.eval(step)
+	if err != nil {
+		return false, err
+	}
+	m.variables[varIdx] = v
+
+	if v > toVal {
+		return false, nil
+	}
+	m.jumpTo(savedPos)
+	m.stack = append(m.stack, varIdx, savedPos, toVal)
+	m.sp += 3
+	return true, nil
+}
+
+// resumePos returns the position immediately after the statement currently
+// executing, used as the FOR/GOSUB/DO return address.
+func (m *Interpreter) resumePos() syntax.Pos {
+	return syntax.Pos{Line: m.pc.Line, Stmt: m.pc.Stmt + 1}
+}
+
+// jumpTo moves execution to pos within the program currently being walked.
+func (m *Interpreter) jumpTo(pos syntax.Pos) {
+	m.pc = pos
+	if pos.Line >= 0 && pos.Line < len(m.runProg.Lines) {
+		m.ln = int(m.runProg.Lines[pos.Line].Num)
+	}
+}
+
+// findLoopEnd scans forward from pos, across line boundaries, for the
+// statement that closes the loop started by a FOR-form Assign: the first
+// "@=" (Until or Next) found, without counting nested loops. It returns the
+// position right after that statement,
+// mirroring the char-scanning "skip to next @" the original interpreter did
+// when a FOR's initial value already failed its bound.
+func (m *Interpreter) findLoopEnd(from syntax.Pos) (syntax.Pos, bool) {
+	pos := from
+	for pos.Line < len(m.runProg.Lines) {
+		line := m.runProg.Lines[pos.Line]
+		for pos.Stmt < len(line.Stmts) {
+			switch line.Stmts[pos.Stmt].(type) {
+			case *syntax.Next, *syntax.Until:
+				return syntax.Pos{Line: pos.Line, Stmt: pos.Stmt + 1}, true
+			}
+			pos.Stmt++
+		}
+		pos = syntax.Pos{Line: pos.Line + 1, Stmt: 0}
+	}
+	return syntax.Pos{}, false
+}
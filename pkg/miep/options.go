@@ -0,0 +1,50 @@
+package miep
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// Options configures a new Interpreter. The zero value is valid: missing
+// fields are filled with the same defaults MIEP used as a standalone CLI
+// (stdio, a time-seeded global-looking Rand). Cancellation is not an
+// option here: it comes from the context passed to Run.
+type Options struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Rand is the source used by the '\'' random-number operator and the
+	// "'=expr" seed statement. Giving each Interpreter its own *rand.Rand
+	// (instead of the package-global rand.Seed/rand.Intn the CLI used to
+	// call) is what makes running several Interpreters concurrently safe.
+	Rand *rand.Rand
+
+	// Sandbox disables *SH (shell) and file access (*LD, *SV, *RS, *AS,
+	// LoadFile, Snapshot, Restore) when true, for running untrusted programs.
+	Sandbox bool
+
+	// Force lets *RS and Restore accept a snapshot whose program hash
+	// doesn't match the currently loaded source. Without it, a mismatched
+	// snapshot is rejected so a restored FOR/GOSUB return address can never
+	// point into code that isn't actually there.
+	Force bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.Stdin == nil {
+		o.Stdin = os.Stdin
+	}
+	if o.Stdout == nil {
+		o.Stdout = os.Stdout
+	}
+	if o.Stderr == nil {
+		o.Stderr = os.Stderr
+	}
+	if o.Rand == nil {
+		o.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return o
+}
@@ -0,0 +1,152 @@
+package miep
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+
+	"github.com/fygar256/game_language/pkg/syntax"
+)
+
+// snapshotMagic/snapshotVersion identify the binary format written by
+// Snapshot, so Restore can reject garbage or a future incompatible format
+// up front instead of failing deep inside gob decoding.
+var snapshotMagic = [4]byte{'M', 'S', 'N', 'P'}
+
+const snapshotVersion = 1
+
+// stackFrame is a gob-friendly stand-in for one element of Interpreter.stack,
+// which holds syntax.Pos, int (a FOR loop's variable index) and int16 (a FOR
+// loop's bound) values side by side; Kind says which field is meaningful.
+type stackFrame struct {
+	Kind byte // 0 = syntax.Pos, 1 = int, 2 = int16
+	Pos  syntax.Pos
+	Int  int
+	I16  int16
+}
+
+// snapshotData is everything Snapshot/Restore round-trip, gob-encoded then
+// gzipped to keep the format compact despite the 64KB memory array.
+type snapshotData struct {
+	Variables   [26]int16
+	Memory      []byte
+	Line        int
+	Stack       []stackFrame
+	SP          int
+	ForMode     int
+	ProgramHash [sha256.Size]byte
+}
+
+// Snapshot serializes variables, memory, the FOR/GOSUB stack and a hash of
+// the currently loaded program into w, so a later Restore against the same
+// (or, with force, a different) program can pick execution back up.
+func (m *Interpreter) Snapshot(w io.Writer) error {
+	if m.opts.Sandbox {
+		return &RuntimeError{Line: m.ln, Err: errSandboxedLoad}
+	}
+
+	data := snapshotData{
+		Variables:   m.variables,
+		Memory:      m.memory[:],
+		Line:        m.ln,
+		SP:          m.sp,
+		ForMode:     m.forMode,
+		ProgramHash: sha256.Sum256(m.source),
+	}
+	for _, frame := range m.stack {
+		switch v := frame.(type) {
+		case syntax.Pos:
+			data.Stack = append(data.Stack, stackFrame{Kind: 0, Pos: v})
+		case int:
+			data.Stack = append(data.Stack, stackFrame{Kind: 1, Int: v})
+		case int16:
+			data.Stack = append(data.Stack, stackFrame{Kind: 2, I16: v})
+		default:
+			return fmt.Errorf("miep: snapshot: unexpected stack frame type %T", v)
+		}
+	}
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(&data); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(snapshotMagic[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(snapshotVersion)); err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(payload.Bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Restore reads a snapshot written by Snapshot and replaces the
+// Interpreter's variables, memory and call stack with it. Unless force is
+// true, it returns ErrProgramMismatch when the snapshot's program hash
+// doesn't match the currently loaded source, since a GOSUB/FOR return
+// address restored against the wrong program would jump into nowhere.
+func (m *Interpreter) Restore(r io.Reader, force bool) error {
+	if m.opts.Sandbox {
+		return &RuntimeError{Line: m.ln, Err: errSandboxedLoad}
+	}
+
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return fmt.Errorf("miep: snapshot: %w", err)
+	}
+	if magic != snapshotMagic {
+		return fmt.Errorf("miep: snapshot: not a MIEP snapshot")
+	}
+	var version uint32
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return fmt.Errorf("miep: snapshot: %w", err)
+	}
+	if version != snapshotVersion {
+		return fmt.Errorf("miep: snapshot: unsupported version %d", version)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("miep: snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	var data snapshotData
+	if err := gob.NewDecoder(gz).Decode(&data); err != nil {
+		return fmt.Errorf("miep: snapshot: %w", err)
+	}
+
+	if !force && data.ProgramHash != sha256.Sum256(m.source) {
+		return ErrProgramMismatch
+	}
+
+	stack := make([]interface{}, len(data.Stack))
+	for i, frame := range data.Stack {
+		switch frame.Kind {
+		case 0:
+			stack[i] = frame.Pos
+		case 1:
+			stack[i] = frame.Int
+		case 2:
+			stack[i] = frame.I16
+		default:
+			return fmt.Errorf("miep: snapshot: unknown stack frame kind %d", frame.Kind)
+		}
+	}
+
+	m.variables = data.Variables
+	copy(m.memory[:], data.Memory)
+	m.ln = data.Line
+	m.stack = stack
+	m.sp = data.SP
+	m.forMode = data.ForMode
+	return nil
+}
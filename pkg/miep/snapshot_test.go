@@ -0,0 +1,35 @@
+package miep
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// *SV and *RS must work written inline alongside other statements on the
+// same line, not just alone on their own line — a direct regression test
+// for the chunk0-4 space-terminates-expression fix, without which
+// "A=42 *SV "f"" parsed as "A = 42 * S" followed by a bare PrintString.
+func TestSnapshotInlineSaveRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snap.miep")
+	source := `1 A=42 *SV "` + path + `"
+2 A=99
+3 *RS "` + path + `"
+4 ?=A /
+`
+	var out bytes.Buffer
+	m := New(Options{Stdout: &out})
+	if err := m.LoadString(source); err != nil {
+		t.Fatalf("LoadString: %v", err)
+	}
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	got := strings.TrimSpace(out.String())
+	if got != "42" {
+		t.Fatalf("output = %q, want %q (A should be restored to its snapshotted value)", got, "42")
+	}
+}
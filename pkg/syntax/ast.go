@@ -0,0 +1,323 @@
+// Package syntax defines the MIEP abstract syntax tree and a parser that
+// turns program source into it once at load time, instead of re-lexing the
+// character buffer on every GOTO/GOSUB and every loop iteration.
+package syntax
+
+// Node is implemented by every AST node. It exists so Fdump can walk an
+// arbitrary tree without a type switch per node kind.
+type Node interface {
+	node()
+}
+
+// Program is the root of a parsed MIEP source file.
+type Program struct {
+	Lines []*Line
+	Index map[int16]int // line number -> index into Lines, for O(1) GOTO/GOSUB
+}
+
+func (*Program) node() {}
+
+// Find returns the index of the line to jump to for a GOTO/GOSUB target:
+// an exact match if one exists, otherwise the first line whose number is
+// greater than target (MIEP, like most line-numbered BASICs, allows
+// jumping to a line number that was never written). Lines is sorted by
+// Num because source lines are required to appear in increasing order.
+func (p *Program) Find(target int16) (int, bool) {
+	if idx, ok := p.Index[target]; ok {
+		return idx, true
+	}
+	lo, hi := 0, len(p.Lines)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if p.Lines[mid].Num < target {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < len(p.Lines) {
+		return lo, true
+	}
+	return 0, false
+}
+
+// Line holds one source line: its line number and the statements on it.
+type Line struct {
+	Num   int16
+	Stmts []Stmt
+}
+
+func (*Line) node() {}
+
+// Pos identifies a statement within a parsed Program, used as a resume
+// point on the FOR/GOSUB/DO stack instead of a raw string offset.
+type Pos struct {
+	Line int // index into Program.Lines
+	Stmt int // index into Line.Stmts; len(Stmts) means "end of line"
+}
+
+// Stmt is implemented by every statement node.
+type Stmt interface {
+	Node
+	stmt()
+}
+
+// Assign is a simple, byte-array (V:expr) or word-array (V(expr)) store.
+type Assign struct {
+	Var   byte // A-Z
+	Index Expr // nil for a simple variable assignment
+	Word  bool // true for V(expr)=, false for V:expr)=
+	Value Expr
+
+	// ForTo is non-nil when this assignment is immediately followed by
+	// ",expr", turning it into the head of a FOR loop.
+	ForTo Expr
+}
+
+func (*Assign) node() {}
+func (*Assign) stmt() {}
+
+// IfStmt is ";=expr" guarding the rest of the line.
+type IfStmt struct {
+	Cond Expr
+}
+
+func (*IfStmt) node() {}
+func (*IfStmt) stmt() {}
+
+// Goto is "#=expr".
+type Goto struct {
+	Target Expr
+}
+
+func (*Goto) node() {}
+func (*Goto) stmt() {}
+
+// Gosub is "!=expr".
+type Gosub struct {
+	Target Expr
+}
+
+func (*Gosub) node() {}
+func (*Gosub) stmt() {}
+
+// Return is "]".
+type Return struct{}
+
+func (*Return) node() {}
+func (*Return) stmt() {}
+
+// Do is "@" (push a DO/UNTIL resume point).
+type Do struct{}
+
+func (*Do) node() {}
+func (*Do) stmt() {}
+
+// Until is "@=(expr)".
+type Until struct {
+	Cond Expr
+}
+
+func (*Until) node() {}
+func (*Until) stmt() {}
+
+// Next is "@=expr" (without a leading paren), closing a FOR loop.
+type Next struct {
+	Step Expr
+}
+
+func (*Next) node() {}
+func (*Next) stmt() {}
+
+// PrintString is a bare "text" literal.
+type PrintString struct {
+	Value string
+}
+
+func (*PrintString) node() {}
+func (*PrintString) stmt() {}
+
+// PrintNewline is "/".
+type PrintNewline struct{}
+
+func (*PrintNewline) node() {}
+func (*PrintNewline) stmt() {}
+
+// PrintSpaces is ".=expr".
+type PrintSpaces struct {
+	Count Expr
+}
+
+func (*PrintSpaces) node() {}
+func (*PrintSpaces) stmt() {}
+
+// PrintDec is "?=expr".
+type PrintDec struct {
+	Value Expr
+}
+
+func (*PrintDec) node() {}
+func (*PrintDec) stmt() {}
+
+// PrintHex4 is "??=expr".
+type PrintHex4 struct {
+	Value Expr
+}
+
+func (*PrintHex4) node() {}
+func (*PrintHex4) stmt() {}
+
+// PrintHex2 is "?$=expr".
+type PrintHex2 struct {
+	Value Expr
+}
+
+func (*PrintHex2) node() {}
+func (*PrintHex2) stmt() {}
+
+// PrintFormatted is "?(width)=expr".
+type PrintFormatted struct {
+	Width Expr
+	Value Expr
+}
+
+func (*PrintFormatted) node() {}
+func (*PrintFormatted) stmt() {}
+
+// PrintChar is "$=expr".
+type PrintChar struct {
+	Value Expr
+}
+
+func (*PrintChar) node() {}
+func (*PrintChar) stmt() {}
+
+// Seed is "'=expr".
+type Seed struct {
+	Value Expr
+}
+
+func (*Seed) node() {}
+func (*Seed) stmt() {}
+
+// OptCommand is a "*xx" two-letter optional command (LD/QU/TN/TF/SH/FM/SV/RS/...).
+type OptCommand struct {
+	Name string // upper-cased two letter code
+	Arg  Expr   // non-nil for commands that take "=expr" (e.g. *FM=n)
+	File string // non-nil (non-empty) for commands that take a filename (*LD)
+}
+
+func (*OptCommand) node() {}
+func (*OptCommand) stmt() {}
+
+// Expr is implemented by every expression node.
+type Expr interface {
+	Node
+	expr()
+}
+
+// BinOp is a binary operator application: +, -, *, /, =, <, >, N (<>), A (<=), B (>=).
+type BinOp struct {
+	Op   byte
+	L, R Expr
+}
+
+func (*BinOp) node() {}
+func (*BinOp) expr() {}
+
+// UnaryOp is a prefix operator: +, -, # (logical not), ' (random), % (last mod result).
+type UnaryOp struct {
+	Op byte
+	X  Expr
+}
+
+func (*UnaryOp) node() {}
+func (*UnaryOp) expr() {}
+
+// Var is a simple A-Z variable reference.
+type Var struct {
+	Name byte
+}
+
+func (*Var) node() {}
+func (*Var) expr() {}
+
+// Const is a literal decimal, hex ($xx) or one/two-character string constant.
+type Const struct {
+	Value int16
+}
+
+func (*Const) node() {}
+func (*Const) expr() {}
+
+// ByteArray is "V:expr)", a byte read from memory at variables[V]+expr.
+type ByteArray struct {
+	Var   byte
+	Index Expr
+}
+
+func (*ByteArray) node() {}
+func (*ByteArray) expr() {}
+
+// WordArray is "V(expr)", a little-endian word read from memory at variables[V]+expr*2.
+type WordArray struct {
+	Var   byte
+	Index Expr
+}
+
+func (*WordArray) node() {}
+func (*WordArray) expr() {}
+
+// Assemble is "*AS"file",expr": two-pass assemble file into memory at the
+// address expr evaluates to.
+type Assemble struct {
+	File   string
+	Origin Expr
+}
+
+func (*Assemble) node() {}
+func (*Assemble) stmt() {}
+
+// Disassemble is "*DA expr,expr": disassemble the memory range [Start, End)
+// to stdout.
+type Disassemble struct {
+	Start, End Expr
+}
+
+func (*Disassemble) node() {}
+func (*Disassemble) stmt() {}
+
+// Execute is "*EX expr" (optionally "*EX expr,expr"): run native 6502 code
+// in memory starting at Address until BRK, or until PC reaches Stop if
+// given.
+type Execute struct {
+	Address Expr
+	Stop    Expr // nil if not given
+}
+
+func (*Execute) node() {}
+func (*Execute) stmt() {}
+
+// CPUReg is "&r", a pseudo-variable reading one register of the 6502
+// emulated by the most recent *EX: A, X, Y, S (stack pointer), P (status)
+// or C (program counter). Unlike A-Z, these read emulator state rather
+// than a BASIC variable, so they get their own sigil instead of aliasing
+// the variable of the same letter.
+type CPUReg struct {
+	Reg byte
+}
+
+func (*CPUReg) node() {}
+func (*CPUReg) expr() {}
+
+// GetCh is "$" outside of a hex-digit context: read one byte from stdin.
+type GetCh struct{}
+
+func (*GetCh) node() {}
+func (*GetCh) expr() {}
+
+// Input is "?" in term position: read and parse a line from stdin.
+type Input struct{}
+
+func (*Input) node() {}
+func (*Input) expr() {}
@@ -0,0 +1,77 @@
+package syntax
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Fdump writes a reflect-based indented dump of an AST node to w, for the
+// "-dump" CLI flag and for debugging the parser. It does not attempt to be
+// a stable or round-trippable format, only a readable one.
+func Fdump(w io.Writer, n Node) {
+	fdump(w, reflect.ValueOf(n), 0)
+}
+
+func fdump(w io.Writer, v reflect.Value, depth int) {
+	indent := func() {
+		for i := 0; i < depth; i++ {
+			fmt.Fprint(w, "  ")
+		}
+	}
+
+	if !v.IsValid() {
+		indent()
+		fmt.Fprintln(w, "<nil>")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			indent()
+			fmt.Fprintln(w, "<nil>")
+			return
+		}
+		fdump(w, v.Elem(), depth)
+	case reflect.Struct:
+		indent()
+		fmt.Fprintf(w, "%s\n", v.Type())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fv := v.Field(i)
+			switch fv.Kind() {
+			case reflect.Ptr, reflect.Interface, reflect.Struct, reflect.Slice, reflect.Map:
+				for j := 0; j < depth+1; j++ {
+					fmt.Fprint(w, "  ")
+				}
+				fmt.Fprintf(w, "%s:\n", field.Name)
+				fdump(w, fv, depth+2)
+			default:
+				for j := 0; j < depth+1; j++ {
+					fmt.Fprint(w, "  ")
+				}
+				fmt.Fprintf(w, "%s: %v\n", field.Name, fv.Interface())
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			indent()
+			fmt.Fprintln(w, "[]")
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			fdump(w, v.Index(i), depth)
+		}
+	case reflect.Map:
+		indent()
+		fmt.Fprintf(w, "map[%d entries]\n", v.Len())
+	default:
+		indent()
+		fmt.Fprintf(w, "%v\n", v.Interface())
+	}
+}
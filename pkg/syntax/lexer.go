@@ -0,0 +1,212 @@
+package syntax
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Kind classifies a Token.
+type Kind int
+
+const (
+	EOF Kind = iota
+	Newline
+	Ident  // a run of letters; a variable name uses its first byte, a "*xx" command its first two
+	Number // a run of decimal digits
+	Hex    // the hex digits following a '$' that turned out to be a numeric literal, not a GetCh
+	String // the contents of a "..." literal, quotes stripped
+	Punct  // a single punctuation byte, verbatim in Literal
+)
+
+// Position is a 1-based line/column in the source a Token or error came from.
+type Position struct {
+	Line, Col int
+}
+
+// Token is one lexical unit of MIEP source.
+type Token struct {
+	Kind    Kind
+	Literal string
+	Pos     Position
+
+	// PrecededBySpace records whether at least one space byte was skipped
+	// before this token. The baseline interpreter's getOperator2 never
+	// skipped spaces at all, so a space before an operator ended the
+	// expression; operator2 uses this flag to restore that behavior now
+	// that lex() skips spaces uniformly ahead of every token.
+	PrecededBySpace bool
+}
+
+// Lexer tokenizes MIEP source one Token at a time. stringLexer reads from an
+// in-memory string; readerLexer streams from an io.Reader so a large program
+// doesn't need to be loaded into memory before parsing can start. Neither
+// lexer understands MIEP's grammar beyond the single '$'-followed-by-hex-
+// digit special case (disambiguating a hex literal from the Getch '$'
+// operator) — everything else is resolved by the parser.
+type Lexer interface {
+	Next() Token
+	Pos() Position
+}
+
+// charSource is the minimal byte-at-a-time interface both lexers tokenize
+// over, so the actual tokenizing logic (lex) is written once.
+type charSource interface {
+	current() byte
+	peek(offset int) byte
+	advance()
+	pos() Position
+}
+
+// stringLexer lexes a source string already fully in memory.
+type stringLexer struct{ src *stringSource }
+
+func newStringLexer(s string) *stringLexer {
+	return &stringLexer{src: &stringSource{s: s, line: 1, col: 1}}
+}
+
+func (l *stringLexer) Next() Token   { return lex(l.src) }
+func (l *stringLexer) Pos() Position { return l.src.pos() }
+
+type stringSource struct {
+	s         string
+	i         int
+	line, col int
+}
+
+func (s *stringSource) current() byte {
+	if s.i < len(s.s) {
+		return s.s[s.i]
+	}
+	return 0
+}
+
+func (s *stringSource) peek(offset int) byte {
+	if s.i+offset < len(s.s) {
+		return s.s[s.i+offset]
+	}
+	return 0
+}
+
+func (s *stringSource) advance() {
+	if s.i >= len(s.s) {
+		return
+	}
+	if s.s[s.i] == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	s.i++
+}
+
+func (s *stringSource) pos() Position { return Position{Line: s.line, Col: s.col} }
+
+// readerLexer streams tokens from an io.Reader, for programs too large (or
+// too unbounded, e.g. a REPL's stdin) to slurp into a string up front.
+type readerLexer struct{ src *readerSource }
+
+func newReaderLexer(r io.Reader) *readerLexer {
+	return &readerLexer{src: &readerSource{r: bufio.NewReaderSize(r, 4096), line: 1, col: 1}}
+}
+
+func (l *readerLexer) Next() Token   { return lex(l.src) }
+func (l *readerLexer) Pos() Position { return l.src.pos() }
+
+type readerSource struct {
+	r         *bufio.Reader
+	line, col int
+}
+
+func (s *readerSource) current() byte { return s.peek(0) }
+
+func (s *readerSource) peek(offset int) byte {
+	b, err := s.r.Peek(offset + 1)
+	if err != nil || len(b) <= offset {
+		return 0
+	}
+	return b[offset]
+}
+
+func (s *readerSource) advance() {
+	b, err := s.r.ReadByte()
+	if err != nil {
+		return
+	}
+	if b == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+}
+
+func (s *readerSource) pos() Position { return Position{Line: s.line, Col: s.col} }
+
+// lex reads and classifies the next token from src, skipping leading spaces.
+// It is the one place that understands MIEP's character classes, shared by
+// both stringLexer and readerLexer.
+func lex(src charSource) Token {
+	sawSpace := false
+	for src.current() == ' ' {
+		sawSpace = true
+		src.advance()
+	}
+
+	pos := src.pos()
+	c := src.current()
+
+	tok := lexOne(src, pos, c)
+	tok.PrecededBySpace = sawSpace
+	return tok
+}
+
+func lexOne(src charSource, pos Position, c byte) Token {
+	switch {
+	case c == 0:
+		return Token{Kind: EOF, Pos: pos}
+	case c == '\n':
+		src.advance()
+		return Token{Kind: Newline, Pos: pos}
+	case c == '"':
+		src.advance()
+		var sb strings.Builder
+		for src.current() != '"' && src.current() != 0 {
+			sb.WriteByte(src.current())
+			src.advance()
+		}
+		if src.current() == '"' {
+			src.advance()
+		}
+		return Token{Kind: String, Literal: sb.String(), Pos: pos}
+	case c == '$':
+		src.advance()
+		if isXDigit(src.current()) {
+			var sb strings.Builder
+			for isXDigit(src.current()) {
+				sb.WriteByte(src.current())
+				src.advance()
+			}
+			return Token{Kind: Hex, Literal: sb.String(), Pos: pos}
+		}
+		return Token{Kind: Punct, Literal: "$", Pos: pos}
+	case isDigit(c):
+		var sb strings.Builder
+		for isDigit(src.current()) {
+			sb.WriteByte(src.current())
+			src.advance()
+		}
+		return Token{Kind: Number, Literal: sb.String(), Pos: pos}
+	case isAlpha(c):
+		var sb strings.Builder
+		for isAlpha(src.current()) {
+			sb.WriteByte(src.current())
+			src.advance()
+		}
+		return Token{Kind: Ident, Literal: sb.String(), Pos: pos}
+	default:
+		src.advance()
+		return Token{Kind: Punct, Literal: string(c), Pos: pos}
+	}
+}
@@ -0,0 +1,635 @@
+package syntax
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseError carries a structured position so callers can render
+// "file:line:col: message" instead of the interpreter's old bare
+// "Syntaxerror in <ln>".
+type ParseError struct {
+	File   string
+	Line   int
+	Col    int
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Col, e.Reason)
+}
+
+// parser turns a Token stream into a *Program in a single pass. It holds no
+// lexing state of its own beyond one token of lookahead (tok), so term,
+// expression and friends never touch source bytes directly — that's the
+// Lexer's job, and a different Lexer (reading from a string or streaming
+// from an io.Reader) can be dropped in without changing any parsing code.
+type parser struct {
+	file string
+	lex  Lexer
+	tok  Token
+}
+
+func newParser(file string, lex Lexer) *parser {
+	p := &parser{file: file, lex: lex}
+	p.tok = lex.Next()
+	return p
+}
+
+func (p *parser) advance() {
+	p.tok = p.lex.Next()
+}
+
+// Parse compiles source into a Program once at load time. The returned
+// Program's Index maps line numbers to Lines slots so GOTO/GOSUB no longer
+// need to re-scan the source on every jump.
+func Parse(source []byte) (*Program, error) {
+	return ParseFile("", source)
+}
+
+// ParseFile is like Parse but records file in any returned *ParseError. It
+// lexes source from memory as a string; ParseReader streams instead.
+func ParseFile(file string, source []byte) (*Program, error) {
+	return parseProgram(newParser(file, newStringLexer(string(source))))
+}
+
+// ParseReader is like ParseFile but streams tokens from r via a readerLexer
+// instead of requiring the whole program up front, for sources too large
+// (or too open-ended, e.g. piped stdin) to buffer as a string.
+func ParseReader(file string, r io.Reader) (*Program, error) {
+	return parseProgram(newParser(file, newReaderLexer(r)))
+}
+
+func parseProgram(p *parser) (*Program, error) {
+	prog := &Program{Index: make(map[int16]int)}
+
+	// A leading '#' line is a comment and is skipped entirely.
+	if p.tok.Kind == Punct && p.tok.Literal == "#" {
+		p.skipToNewline()
+	}
+
+	for p.tok.Kind == Number {
+		num := p.decimalValue()
+
+		line, err := p.parseLine(num)
+		if err != nil {
+			return nil, err
+		}
+		prog.Index[num] = len(prog.Lines)
+		prog.Lines = append(prog.Lines, line)
+	}
+
+	return prog, nil
+}
+
+func (p *parser) parseLine(num int16) (*Line, error) {
+	line := &Line{Num: num}
+
+	for {
+		if p.tok.Kind == EOF || p.tok.Kind == Newline {
+			if p.tok.Kind == Newline {
+				p.advance()
+			}
+			return line, nil
+		}
+
+		stmt, err := p.parseStmt()
+		if err != nil {
+			return nil, err
+		}
+		if stmt != nil {
+			line.Stmts = append(line.Stmts, stmt)
+		}
+	}
+}
+
+func (p *parser) parseStmt() (Stmt, error) {
+	if p.tok.Kind == String {
+		return &PrintString{Value: p.stringLiteral()}, nil
+	}
+	if p.tok.Kind == Ident {
+		return p.parseAssign()
+	}
+	if p.tok.Kind != Punct {
+		return nil, p.errorf("Syntaxerror")
+	}
+
+	switch p.tok.Literal {
+	case "/":
+		p.advance()
+		return &PrintNewline{}, nil
+	case ".":
+		p.advance()
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &PrintSpaces{Count: v}, nil
+	case "*":
+		p.advance()
+		return p.parseOptCommand()
+	case "?":
+		p.advance()
+		return p.parsePrintCommand()
+	case "'":
+		p.advance()
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &Seed{Value: v}, nil
+	case "$":
+		p.advance()
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &PrintChar{Value: v}, nil
+	case "#":
+		p.advance()
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &Goto{Target: v}, nil
+	case "!":
+		p.advance()
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &Gosub{Target: v}, nil
+	case "]":
+		p.advance()
+		return &Return{}, nil
+	case "@":
+		p.advance()
+		return p.parseLoopCommand()
+	case ";":
+		p.advance()
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &IfStmt{Cond: v}, nil
+	}
+
+	return nil, p.errorf("Syntaxerror")
+}
+
+func (p *parser) parsePrintCommand() (Stmt, error) {
+	if p.tok.Kind == Punct {
+		switch p.tok.Literal {
+		case "=":
+			p.advance()
+			v, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return &PrintDec{Value: v}, nil
+		case "?":
+			p.advance()
+			if err := p.expect('='); err != nil {
+				return nil, err
+			}
+			v, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return &PrintHex4{Value: v}, nil
+		case "$":
+			p.advance()
+			if err := p.expect('='); err != nil {
+				return nil, err
+			}
+			v, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return &PrintHex2{Value: v}, nil
+		case "(":
+			p.advance()
+			width, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(')'); err != nil {
+				return nil, err
+			}
+			if err := p.expect('='); err != nil {
+				return nil, err
+			}
+			v, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return &PrintFormatted{Width: width, Value: v}, nil
+		}
+	}
+	return nil, p.errorf("Syntaxerror")
+}
+
+func (p *parser) parseLoopCommand() (Stmt, error) {
+	if !(p.tok.Kind == Punct && p.tok.Literal == "=") {
+		return &Do{}, nil
+	}
+	p.advance()
+	if p.tok.Kind == Punct && p.tok.Literal == "(" {
+		p.advance()
+		cond, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return &Until{Cond: cond}, nil
+	}
+	v, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	return &Next{Step: v}, nil
+}
+
+func (p *parser) parseOptCommand() (Stmt, error) {
+	if p.tok.Kind != Ident || len(p.tok.Literal) < 2 {
+		return nil, p.errorf("Syntaxerror")
+	}
+	name := strings.ToUpper(p.tok.Literal[:2])
+	p.advance()
+
+	switch name {
+	case "LD", "SV", "RS":
+		var sb strings.Builder
+		for p.tok.Kind != Newline && p.tok.Kind != EOF {
+			sb.WriteString(p.tok.Literal)
+			p.advance()
+		}
+		return &OptCommand{Name: name, File: sb.String()}, nil
+	case "FM":
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &OptCommand{Name: name, Arg: v}, nil
+	case "QU", "TN", "TF", "SH":
+		return &OptCommand{Name: name}, nil
+	case "AS":
+		file := p.stringLiteral()
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		origin, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &Assemble{File: file, Origin: origin}, nil
+	case "DA":
+		start, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		end, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &Disassemble{Start: start, End: end}, nil
+	case "EX":
+		addr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		ex := &Execute{Address: addr}
+		if p.tok.Kind == Punct && p.tok.Literal == "," {
+			p.advance()
+			stop, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			ex.Stop = stop
+		}
+		return ex, nil
+	}
+	return nil, p.errorf("Syntaxerror")
+}
+
+func (p *parser) parseAssign() (Stmt, error) {
+	varName := toUpper(p.variable())
+	a := &Assign{Var: varName}
+	p.advance()
+
+	if p.tok.Kind == Punct && p.tok.Literal == ":" {
+		p.advance()
+		idx, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		a.Index = idx
+		a.Word = false
+	} else if p.tok.Kind == Punct && p.tok.Literal == "(" {
+		p.advance()
+		idx, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		a.Index = idx
+		a.Word = true
+	}
+
+	if err := p.expect('='); err != nil {
+		return nil, err
+	}
+	v, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	a.Value = v
+
+	if p.tok.Kind == Punct && p.tok.Literal == "," {
+		p.advance()
+		toVal, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		a.ForTo = toVal
+	}
+
+	return a, nil
+}
+
+// expression and term mirror the original interpreter's recursive-descent
+// operator grammar, but build Expr nodes instead of evaluating eagerly, and
+// read from the Lexer's token stream instead of a char buffer.
+func (p *parser) expression() (Expr, error) {
+	v, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.operator2()
+		if op == 0 {
+			break
+		}
+		rhs, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		v = &BinOp{Op: op, L: v, R: rhs}
+	}
+
+	return v, nil
+}
+
+func (p *parser) term() (Expr, error) {
+	if p.tok.Kind == Punct && p.tok.Literal == "(" {
+		p.advance()
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+
+	if p.tok.Kind == Ident {
+		varName := toUpper(p.variable())
+		p.advance()
+		if p.tok.Kind == Punct && p.tok.Literal == ":" {
+			p.advance()
+			idx, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(')'); err != nil {
+				return nil, err
+			}
+			return &ByteArray{Var: varName, Index: idx}, nil
+		}
+		if p.tok.Kind == Punct && p.tok.Literal == "(" {
+			p.advance()
+			idx, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(')'); err != nil {
+				return nil, err
+			}
+			return &WordArray{Var: varName, Index: idx}, nil
+		}
+		return &Var{Name: varName}, nil
+	}
+
+	if p.tok.Kind == Punct && p.tok.Literal == "$" {
+		p.advance()
+		return &GetCh{}, nil
+	}
+
+	if p.tok.Kind == Punct && p.tok.Literal == "&" {
+		p.advance()
+		reg := byte(0)
+		if p.tok.Kind == Ident {
+			reg = strings.ToUpper(p.tok.Literal)[0]
+		}
+		p.advance()
+		return &CPUReg{Reg: reg}, nil
+	}
+
+	if p.tok.Kind == Punct && p.tok.Literal == "?" {
+		p.advance()
+		return &Input{}, nil
+	}
+
+	if v, ok := p.constant(); ok {
+		return &Const{Value: v}, nil
+	}
+
+	if op := p.operator1(); op != 0 {
+		x, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryOp{Op: op, X: x}, nil
+	}
+
+	return &Const{Value: 0}, nil
+}
+
+// --- token-level helpers, each consuming exactly the token(s) it names ---
+
+func (p *parser) expect(c byte) error {
+	if p.tok.Kind == Punct && len(p.tok.Literal) == 1 && p.tok.Literal[0] == c {
+		p.advance()
+		return nil
+	}
+	return p.errorf("Syntaxerror: expected %q, got %q", c, p.tok.Literal)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{File: p.file, Line: p.tok.Pos.Line, Col: p.tok.Pos.Col, Reason: fmt.Sprintf(format, args...)}
+}
+
+// variable returns the current token's first letter without advancing past
+// it (callers that also need to inspect what follows, like term's array
+// syntax, advance explicitly); a multi-letter identifier still only ever
+// names the variable of its first letter, same as the original interpreter.
+func (p *parser) variable() byte {
+	if p.tok.Kind != Ident {
+		return 0
+	}
+	return p.tok.Literal[0]
+}
+
+func (p *parser) decimalValue() int16 {
+	if p.tok.Kind != Number {
+		return -1
+	}
+	val, _ := strconv.Atoi(p.tok.Literal)
+	p.advance()
+	return int16(val)
+}
+
+func (p *parser) stringLiteral() string {
+	if p.tok.Kind != String {
+		return ""
+	}
+	s := p.tok.Literal
+	p.advance()
+	return s
+}
+
+func (p *parser) constant() (int16, bool) {
+	switch p.tok.Kind {
+	case String:
+		s := p.tok.Literal
+		p.advance()
+		v := int16(0)
+		if len(s) > 0 {
+			v = int16(s[0])
+		}
+		if len(s) > 1 {
+			v += int16(s[1]) * 256
+		}
+		return v, true
+	case Hex:
+		val, _ := strconv.ParseInt(p.tok.Literal, 16, 32)
+		p.advance()
+		return int16(val), true
+	case Number:
+		val, _ := strconv.Atoi(p.tok.Literal)
+		p.advance()
+		return int16(val), true
+	}
+	return 0, false
+}
+
+func (p *parser) operator1() byte {
+	if p.tok.Kind != Punct {
+		return 0
+	}
+	switch p.tok.Literal {
+	case "+", "-", "'", "#", "%":
+		c := p.tok.Literal[0]
+		p.advance()
+		return c
+	}
+	return 0
+}
+
+// operator2 consumes a binary operator token, but — matching the baseline
+// interpreter's getOperator2, which never skipped spaces before checking for
+// an operator — refuses to treat a space-preceded token as one. A space is
+// how MIEP programs end one statement and start the next (e.g. "?=A /" is
+// "print A" followed by an empty-expression statement, not a division), so
+// without this check expression() would greedily reach across the space and
+// swallow the next statement's leading operator.
+func (p *parser) operator2() byte {
+	if p.tok.Kind != Punct || p.tok.PrecededBySpace {
+		return 0
+	}
+	switch p.tok.Literal {
+	case "=", "+", "-", "*", "/":
+		c := p.tok.Literal[0]
+		p.advance()
+		return c
+	case "<":
+		p.advance()
+		if p.tok.Kind == Punct && p.tok.Literal == ">" {
+			p.advance()
+			return 'N'
+		}
+		if p.tok.Kind == Punct && p.tok.Literal == "=" {
+			p.advance()
+			return 'A'
+		}
+		return '<'
+	case ">":
+		p.advance()
+		if p.tok.Kind == Punct && p.tok.Literal == "=" {
+			p.advance()
+			return 'B'
+		}
+		return '>'
+	}
+	return 0
+}
+
+func (p *parser) skipToNewline() {
+	for p.tok.Kind != Newline && p.tok.Kind != EOF {
+		p.advance()
+	}
+	if p.tok.Kind == Newline {
+		p.advance()
+	}
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isXDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func toUpper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
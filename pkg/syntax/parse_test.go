@@ -0,0 +1,82 @@
+package syntax
+
+import "testing"
+
+// A space terminates an expression in MIEP, same as the baseline
+// interpreter's getOperator2 (which never skipped spaces before checking
+// for an operator). "?=A /" is "print A" followed by an empty-expression
+// print-newline, not "print (A / <nothing>)".
+func TestSpaceTerminatesExpression(t *testing.T) {
+	prog, err := Parse([]byte("1 A=7 ?=A /\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(prog.Lines) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(prog.Lines))
+	}
+	stmts := prog.Lines[0].Stmts
+	if len(stmts) != 3 {
+		t.Fatalf("expected 3 statements, got %d: %#v", len(stmts), stmts)
+	}
+
+	print, ok := stmts[1].(*PrintDec)
+	if !ok {
+		t.Fatalf("stmt 1 = %T, want *PrintDec", stmts[1])
+	}
+	v, ok := print.Value.(*Var)
+	if !ok || v.Name != 'A' {
+		t.Fatalf("PrintDec.Value = %#v, want Var{A}", print.Value)
+	}
+
+	if _, ok := stmts[2].(*PrintNewline); !ok {
+		t.Fatalf("stmt 2 = %T, want *PrintNewline", stmts[2])
+	}
+}
+
+// Inside a FOR body, "?=A /" must not swallow the "/" into the print
+// expression either, so the "@=A+1" that follows still parses as Next.
+func TestSpaceTerminatesExpressionInForBody(t *testing.T) {
+	prog, err := Parse([]byte("1 A=1,3 ?=A / @=A+1\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	stmts := prog.Lines[0].Stmts
+	if len(stmts) != 4 {
+		t.Fatalf("expected 4 statements, got %d: %#v", len(stmts), stmts)
+	}
+	if _, ok := stmts[2].(*PrintNewline); !ok {
+		t.Fatalf("stmt 2 = %T, want *PrintNewline", stmts[2])
+	}
+	next, ok := stmts[3].(*Next)
+	if !ok {
+		t.Fatalf("stmt 3 = %T, want *Next", stmts[3])
+	}
+	if _, ok := next.Step.(*BinOp); !ok {
+		t.Fatalf("Next.Step = %#v, want *BinOp (A+1)", next.Step)
+	}
+}
+
+// "A=5 *TN" assigns A and then turns trace mode on; the "*" must not be
+// read as a multiplication across the space that separates the two
+// statements.
+func TestSpaceTerminatesExpressionBeforeOptCommand(t *testing.T) {
+	prog, err := Parse([]byte("1 A=5 *TN\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	stmts := prog.Lines[0].Stmts
+	if len(stmts) != 2 {
+		t.Fatalf("expected 2 statements, got %d: %#v", len(stmts), stmts)
+	}
+	assign, ok := stmts[0].(*Assign)
+	if !ok {
+		t.Fatalf("stmt 0 = %T, want *Assign", stmts[0])
+	}
+	if _, ok := assign.Value.(*Const); !ok {
+		t.Fatalf("Assign.Value = %#v, want Const{5}", assign.Value)
+	}
+	opt, ok := stmts[1].(*OptCommand)
+	if !ok || opt.Name != "TN" {
+		t.Fatalf("stmt 1 = %#v, want OptCommand{TN}", stmts[1])
+	}
+}